@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/sthayduk/hibp-checker/internal/hibp"
+)
+
+var (
+	bloomInput         string
+	bloomOutput        string
+	bloomFalsePositive float64
+)
+
+var bloomCmd = &cobra.Command{
+	Use:   "bloom",
+	Short: "Build offline Bloom filters for --offline checks",
+}
+
+var bloomBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a Bloom filter from a Pwned Passwords download",
+	Long: `build reads a full Pwned Passwords download (the official
+"hash:count" text format, one hash per line -- either the SHA-1 or NTLM
+ordered list) and serializes a Bloom filter sized for --fp-rate. The
+result can be passed to "hibp-checker check --offline" for fully
+air-gapped audits.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bloomInput == "" {
+			return fmt.Errorf("--input is required")
+		}
+		if bloomOutput == "" {
+			return fmt.Errorf("--output is required")
+		}
+
+		fmt.Printf("Building Bloom filter from %s (false-positive rate %g)...\n", bloomInput, bloomFalsePositive)
+
+		filter, err := hibp.BuildBloomFilterFromFile(bloomInput, bloomFalsePositive)
+		if err != nil {
+			return fmt.Errorf("failed to build bloom filter: %w", err)
+		}
+
+		if err := filter.Save(bloomOutput); err != nil {
+			return fmt.Errorf("failed to save bloom filter: %w", err)
+		}
+
+		fmt.Printf("Wrote Bloom filter to %s\n", bloomOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bloomCmd)
+	bloomCmd.AddCommand(bloomBuildCmd)
+
+	bloomBuildCmd.Flags().StringVarP(&bloomInput, "input", "i", "", "Pwned Passwords download to read (required)")
+	bloomBuildCmd.Flags().StringVarP(&bloomOutput, "output", "o", "", "Path to write the serialized Bloom filter to (required)")
+	bloomBuildCmd.Flags().Float64Var(&bloomFalsePositive, "fp-rate", 1e-6, "Target false-positive rate")
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sthayduk/hibp-checker/internal/hibp"
+)
+
+var (
+	breachAPIKey            string
+	breachDomain            string
+	breachIncludeUnverified bool
+	breachTruncate          bool
+	breachOutputFile        string
+	breachOutputFormat      string
+)
+
+var breachesCmd = &cobra.Command{
+	Use:   "breaches",
+	Short: "Query the HIBP breaches API",
+	Long: `breaches talks to the authenticated Have I Been Pwned breaches API to
+list known breaches, inspect a single breach, list data classes, or check
+whether an account has appeared in any breach or paste.
+
+An API key is required for every subcommand and can be supplied with
+--api-key or the HIBP_API_KEY environment variable. Requests are
+rate-limited to one every 6 seconds per HIBP's authenticated API terms.`,
+}
+
+var breachesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known breaches",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newBreachClient()
+		if err != nil {
+			return err
+		}
+
+		breaches, err := client.ListBreaches(breachDomain, breachIncludeUnverified)
+		if err != nil {
+			return fmt.Errorf("failed to list breaches: %w", err)
+		}
+
+		return writeBreaches(breaches)
+	},
+}
+
+var breachesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show details of a single breach",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newBreachClient()
+		if err != nil {
+			return err
+		}
+
+		breach, err := client.GetBreach(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get breach: %w", err)
+		}
+		if breach == nil {
+			return fmt.Errorf("no breach named %q", args[0])
+		}
+
+		return writeBreaches([]hibp.Breach{*breach})
+	},
+}
+
+var breachesDataClassesCmd = &cobra.Command{
+	Use:   "dataclasses",
+	Short: "List all data classes tracked by HIBP",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newBreachClient()
+		if err != nil {
+			return err
+		}
+
+		classes, err := client.DataClasses()
+		if err != nil {
+			return fmt.Errorf("failed to list data classes: %w", err)
+		}
+
+		for _, c := range classes {
+			fmt.Println(c)
+		}
+		return nil
+	},
+}
+
+var breachesCheckAccountCmd = &cobra.Command{
+	Use:   "check-account <email|file>",
+	Short: "Check one account, or a file of accounts, against breaches and pastes",
+	Long: `check-account looks up a single email address, or every line of a
+file, against the HIBP breachedaccount and pasteaccount endpoints and
+reports which breach(es) and paste(s) each account appeared in.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		client, err := newBreachClient()
+		if err != nil {
+			return err
+		}
+
+		accounts, err := accountsFromArg(args[0])
+		if err != nil {
+			return err
+		}
+
+		out, closeOut, err := openBreachOutput()
+		if err != nil {
+			return err
+		}
+		defer closeOut()
+		// Flush whatever was written so far even if an error below aborts
+		// the run partway through; a failing account shouldn't discard
+		// every result already streamed out.
+		defer func() {
+			if ferr := out.flush(); err == nil {
+				err = ferr
+			}
+		}()
+
+		for _, account := range accounts {
+			breaches, err := client.BreachedAccount(account, breachDomain, breachTruncate, breachIncludeUnverified)
+			if err != nil {
+				return fmt.Errorf("failed to check account %q: %w", account, err)
+			}
+
+			pastes, err := client.PasteAccount(account)
+			if err != nil {
+				return fmt.Errorf("failed to check pastes for %q: %w", account, err)
+			}
+
+			if len(breaches) == 0 && len(pastes) == 0 {
+				continue
+			}
+
+			if err := out.write(account, breaches, pastes); err != nil {
+				return fmt.Errorf("failed to write result: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(breachesCmd)
+	breachesCmd.PersistentFlags().StringVar(&breachAPIKey, "api-key", "", "HIBP API key (or set HIBP_API_KEY)")
+
+	breachesListCmd.Flags().StringVar(&breachDomain, "domain", "", "Restrict results to a single domain")
+	breachesListCmd.Flags().BoolVar(&breachIncludeUnverified, "include-unverified", false, "Include unverified breaches")
+
+	breachesCheckAccountCmd.Flags().StringVar(&breachDomain, "domain", "", "Restrict results to a single domain")
+	breachesCheckAccountCmd.Flags().BoolVar(&breachIncludeUnverified, "include-unverified", false, "Include unverified breaches")
+	breachesCheckAccountCmd.Flags().BoolVar(&breachTruncate, "truncate", false, "Only return breach names, not full details")
+	breachesCheckAccountCmd.Flags().StringVarP(&breachOutputFile, "output", "o", "", "Output file (streamed); defaults to stdout")
+	breachesCheckAccountCmd.Flags().StringVar(&breachOutputFormat, "format", "json", "Output format: json or csv")
+
+	breachesCmd.AddCommand(breachesListCmd)
+	breachesCmd.AddCommand(breachesShowCmd)
+	breachesCmd.AddCommand(breachesDataClassesCmd)
+	breachesCmd.AddCommand(breachesCheckAccountCmd)
+}
+
+// newBreachClient builds a BreachClient from --api-key or HIBP_API_KEY.
+func newBreachClient() (*hibp.BreachClient, error) {
+	key := breachAPIKey
+	if key == "" {
+		key = os.Getenv("HIBP_API_KEY")
+	}
+	if key == "" {
+		return nil, fmt.Errorf("an API key is required: pass --api-key or set HIBP_API_KEY")
+	}
+	return hibp.NewBreachClient(key), nil
+}
+
+// accountsFromArg treats arg as a single email unless it names a readable
+// file, in which case every non-empty line is treated as an account.
+func accountsFromArg(arg string) ([]string, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		file, err := os.Open(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", arg, err)
+		}
+		defer file.Close()
+
+		var accounts []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" {
+				accounts = append(accounts, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", arg, err)
+		}
+		return accounts, nil
+	}
+
+	return []string{arg}, nil
+}
+
+// breachOutput streams check-account results in the requested format.
+type breachOutput struct {
+	format    string
+	writer    *bufio.Writer
+	csvWriter *csv.Writer
+}
+
+func openBreachOutput() (*breachOutput, func(), error) {
+	var w io.Writer = os.Stdout
+	closeFn := func() {}
+
+	if breachOutputFile != "" {
+		file, err := os.Create(breachOutputFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		w = file
+		closeFn = func() { file.Close() }
+	}
+
+	out := &breachOutput{format: breachOutputFormat, writer: bufio.NewWriter(w)}
+	if out.format == "csv" {
+		out.csvWriter = csv.NewWriter(out.writer)
+		if err := out.csvWriter.Write([]string{"account", "breaches", "pastes"}); err != nil {
+			return nil, nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+	return out, closeFn, nil
+}
+
+func (o *breachOutput) write(account string, breaches []hibp.Breach, pastes []hibp.Paste) error {
+	breachNames := make([]string, len(breaches))
+	for i, b := range breaches {
+		breachNames[i] = b.Name
+	}
+	pasteSources := make([]string, len(pastes))
+	for i, p := range pastes {
+		pasteSources[i] = p.Source
+	}
+
+	switch o.format {
+	case "csv":
+		return o.csvWriter.Write([]string{account, strings.Join(breachNames, ";"), strings.Join(pasteSources, ";")})
+	default:
+		record := struct {
+			Account  string        `json:"account"`
+			Breaches []hibp.Breach `json:"breaches"`
+			Pastes   []hibp.Paste  `json:"pastes"`
+		}{account, breaches, pastes}
+
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.writer, string(encoded))
+		return err
+	}
+}
+
+func (o *breachOutput) flush() error {
+	if o.csvWriter != nil {
+		o.csvWriter.Flush()
+		if err := o.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+	return o.writer.Flush()
+}
+
+// writeBreaches prints breaches as indented JSON to stdout.
+func writeBreaches(breaches []hibp.Breach) error {
+	encoded, err := json.MarshalIndent(breaches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode breaches: %w", err)
+	}
+	fmt.Println(string(encoded))
+	return nil
+}
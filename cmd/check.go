@@ -4,36 +4,99 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/sthayduk/hibp-checker/internal/hibp"
 )
 
 var (
-	inputFile  string
-	outputFile string
-	delimiter  string
-	skipHeader bool
-	workers    int
-	limit      int
+	inputFile      string
+	outputFile     string
+	delimiter      string
+	skipHeader     bool
+	workers        int
+	limit          int
+	annotateBreach bool
+	hashMode       string
+	inputFormat    string
+	hashColumn     int
+	statePath      string
+	resumeOnly     bool
+	offlinePath    string
+	offlineOnly    bool
+	cacheDir       string
+	requestsPerSec float64
+	addPadding     bool
 )
 
 var checkCmd = &cobra.Command{
 	Use:   "check",
-	Short: "Check NTLM hashes against HIBP database",
-	Long: `Check NTLM password hashes from a file against the Have I Been Pwned
-Pwned Passwords API. The input file should contain lines in the format:
+	Short: "Check password hashes against HIBP database",
+	Long: `Check password hashes from a file against the Have I Been Pwned
+Pwned Passwords API. By default the input file should contain lines in the
+format:
 account:hash
 
+--format selects a different input layout: hash-list (a bare hash per
+line), secretsdump (secretsdump.py's "user:rid:lmhash:nthash:::" lines,
+NT hash only), pot (a John the Ripper / hashcat pot file, hash column
+selected with --hash-column), or plaintext (a password per line, hashed
+locally before querying so only the hash prefix leaves the machine).
+
+--mode selects sha1 or ntlm, or auto to infer it per line from hash
+length so a single run can consolidate mixed SHA-1/NTLM corpora.
+
 Accounts ending with '$' (computer accounts) are automatically skipped.
 
 Results are streamed to the output file as they are found, so partial
-results are preserved if the process is interrupted.`,
+results are preserved if the process is interrupted.
+
+--state <path> checkpoints progress to an append-only journal so a
+re-run of the same command skips already-queried prefixes and does not
+re-emit results already reported. --resume requires that journal to
+already exist, refusing to start a run under a fresh state path.
+
+--offline <bloom-filter> checks hashes against a local Bloom filter built
+with "hibp-checker bloom build" before querying the network: a miss is
+definitive and skips the network entirely. By default a hit is still
+confirmed over the network, since the filter's false-positive rate means
+a hit alone isn't conclusive; --offline-only trusts the filter outright
+and never touches the network, for fully air-gapped audits. --cache-dir,
+--rps, and --add-padding configure the network client's ETag cache, rate
+limit, and traffic-analysis padding respectively.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if inputFile == "" {
 			return fmt.Errorf("input file is required")
 		}
 
+		if resumeOnly && statePath == "" {
+			return fmt.Errorf("--resume requires --state")
+		}
+
+		mode, err := parseModeFlag(hashMode)
+		if err != nil {
+			return err
+		}
+
+		client := hibp.NewClient(
+			hibp.WithRequestsPerSecond(requestsPerSec),
+			hibp.WithAddPadding(addPadding),
+			hibp.WithCacheDir(cacheDir),
+		)
+		checkerOpts := []hibp.CheckerOption{hibp.WithClient(client)}
+
+		if offlinePath != "" {
+			filter, err := hibp.LoadBloomFilter(offlinePath)
+			if err != nil {
+				return fmt.Errorf("failed to load offline bloom filter: %w", err)
+			}
+			checkerOpts = append(checkerOpts,
+				hibp.WithOfflineSource(hibp.NewBloomOfflineSource(filter)),
+				hibp.WithOfflineOnly(offlineOnly),
+			)
+		}
+
 		// Open output file early for streaming results
 		var resultWriter *hibp.ResultWriter
 		if outputFile != "" {
@@ -53,19 +116,90 @@ results are preserved if the process is interrupted.`,
 			resultWriter = hibp.NewResultWriter(nil)
 		}
 
-		checker := hibp.NewChecker()
+		var journal *hibp.StateJournal
+		checker := hibp.NewChecker(checkerOpts...)
+
+		if statePath != "" {
+			if resumeOnly {
+				if _, err := os.Stat(statePath); err != nil {
+					return fmt.Errorf("--resume requires an existing state file at %s: %w", statePath, err)
+				}
+			}
+
+			if err := checker.LoadState(statePath, resultWriter); err != nil {
+				return fmt.Errorf("failed to load state: %w", err)
+			}
+
+			journal, err = hibp.OpenStateJournal(statePath)
+			if err != nil {
+				return fmt.Errorf("failed to open state file: %w", err)
+			}
+			defer journal.Close()
+		}
 
-		exposedCount, err := checker.CheckFile(inputFile, delimiter, skipHeader, workers, limit, resultWriter)
+		exposedCount, err := checker.CheckFile(inputFile, delimiter, skipHeader, workers, limit, hibp.InputFormat(inputFormat), hashColumn, mode, resultWriter, journal)
 		if err != nil {
 			return fmt.Errorf("failed to check file: %w", err)
 		}
 
 		fmt.Printf("\nTotal exposed accounts: %d\n", exposedCount)
 
+		if annotateBreach {
+			if err := annotateExposedAccounts(checker); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	},
 }
 
+// annotateExposedAccounts cross-references every exposed account against
+// the HIBP breaches API and prints which breach(es) it appeared in. It
+// requires an API key and is subject to the breaches API's 6-second-per-key
+// rate limit, so it can take a while on large result sets. A missing API key
+// or the first failed lookup stops the annotation and is returned as an
+// error.
+func annotateExposedAccounts(checker *hibp.Checker) error {
+	client, err := newBreachClient()
+	if err != nil {
+		return fmt.Errorf("failed to annotate exposed accounts: %w", err)
+	}
+
+	accounts := checker.ExposedAccounts()
+	fmt.Printf("\nCross-referencing %d exposed accounts against the breaches API...\n", len(accounts))
+
+	for _, account := range accounts {
+		breaches, err := client.BreachedAccount(account, "", true, false)
+		if err != nil {
+			return fmt.Errorf("failed to check breaches for %s: %w", account, err)
+		}
+
+		if len(breaches) == 0 {
+			fmt.Printf("%s: no known breaches\n", account)
+			continue
+		}
+
+		names := make([]string, len(breaches))
+		for i, b := range breaches {
+			names[i] = b.Name
+		}
+		fmt.Printf("%s: %s\n", account, strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// parseModeFlag validates the --mode flag and converts it to a hibp.Mode.
+func parseModeFlag(value string) (hibp.Mode, error) {
+	switch hibp.Mode(value) {
+	case hibp.ModeSHA1, hibp.ModeNTLM, hibp.ModeAuto:
+		return hibp.Mode(value), nil
+	default:
+		return "", fmt.Errorf("invalid --mode %q: must be sha1, ntlm, or auto", value)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(checkCmd)
 
@@ -75,6 +209,18 @@ func init() {
 	checkCmd.Flags().BoolVarP(&skipHeader, "skip-header", "s", false, "Skip the first line (header row)")
 	checkCmd.Flags().IntVarP(&workers, "workers", "w", 10, "Number of concurrent workers for API queries")
 	checkCmd.Flags().IntVarP(&limit, "limit", "l", 0, "Limit number of accounts to check (0 = no limit)")
+	checkCmd.Flags().BoolVar(&annotateBreach, "annotate-breaches", false, "Cross-reference exposed accounts against the breaches API (requires --api-key or HIBP_API_KEY)")
+	checkCmd.Flags().StringVar(&breachAPIKey, "api-key", "", "HIBP API key for --annotate-breaches (or set HIBP_API_KEY)")
+	checkCmd.Flags().StringVar(&hashMode, "mode", "ntlm", "Hash mode: sha1, ntlm, or auto (infer per line from hash length)")
+	checkCmd.Flags().StringVar(&inputFormat, "format", "account-hash", "Input format: account-hash, hash-list, secretsdump, pot, or plaintext")
+	checkCmd.Flags().IntVar(&hashColumn, "hash-column", 0, "Colon-separated column containing the hash, for --format pot")
+	checkCmd.Flags().StringVar(&statePath, "state", "", "Checkpoint progress to this journal file, enabling resumable runs")
+	checkCmd.Flags().BoolVar(&resumeOnly, "resume", false, "Require --state to already exist; refuse to start a fresh run")
+	checkCmd.Flags().StringVar(&offlinePath, "offline", "", "Check against this local Bloom filter before the network (see: hibp-checker bloom build)")
+	checkCmd.Flags().BoolVar(&offlineOnly, "offline-only", false, "Trust --offline hits outright and never query the network, for air-gapped audits")
+	checkCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache prefix responses and ETags under this directory")
+	checkCmd.Flags().Float64Var(&requestsPerSec, "rps", 0, "Maximum requests per second across all workers (0 = unlimited)")
+	checkCmd.Flags().BoolVar(&addPadding, "add-padding", false, "Send the Add-Padding header to mitigate traffic analysis of prefix queries")
 
 	checkCmd.MarkFlagRequired("input")
 }
@@ -9,12 +9,13 @@ import (
 
 var rootCmd = &cobra.Command{
 	Use:   "hibp-checker",
-	Short: "Check NTLM password hashes against Have I Been Pwned",
-	Long: `hibp-checker is a CLI tool that checks NTLM password hashes
-against the Have I Been Pwned Pwned Passwords API.
+	Short: "Check password hashes against Have I Been Pwned",
+	Long: `hibp-checker is a CLI tool that checks SHA-1 or NTLM password hashes
+against the Have I Been Pwned Pwned Passwords API, and can cross-reference
+accounts against the HIBP breaches API.
 
-It reads a file containing account:hash pairs and identifies
-which passwords have been exposed in data breaches.`,
+It reads a file of account:hash pairs (or other supported formats) and
+identifies which passwords have been exposed in data breaches.`,
 }
 
 func Execute() {
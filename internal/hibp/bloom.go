@@ -0,0 +1,218 @@
+package hibp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"strings"
+)
+
+// OfflineSource looks up whether a hash is present in a local corpus of
+// pwned password hashes, without making any network request. Implementations
+// may report false positives but must never report a false negative, so
+// Checker can treat a miss as a definitive "not exposed".
+type OfflineSource interface {
+	Contains(hash string) bool
+}
+
+// BloomFilter is a fixed-size Bloom filter over hex-encoded password
+// hashes, sized for a target false-positive rate. It supports the ~850M
+// entries of the full Pwned Passwords download without needing to hold
+// every hash in memory.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    uint64 // number of hash functions
+}
+
+// NewBloomFilter creates an empty filter sized for n expected entries at
+// the given false-positive rate (e.g. 1e-6).
+func NewBloomFilter(n uint64, falsePositiveRate float64) *BloomFilter {
+	m := optimalBits(n, falsePositiveRate)
+	k := optimalHashCount(m, n)
+
+	return &BloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func optimalBits(n uint64, falsePositiveRate float64) uint64 {
+	if n == 0 {
+		n = 1
+	}
+	bits := math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	if bits < 64 {
+		bits = 64
+	}
+	return uint64(bits)
+}
+
+func optimalHashCount(m, n uint64) uint64 {
+	if n == 0 {
+		return 1
+	}
+	k := math.Round(float64(m) / float64(n) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+	return uint64(k)
+}
+
+// Add inserts hash into the filter.
+func (f *BloomFilter) Add(hash string) {
+	h1, h2 := splitHash(hash)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains reports whether hash may be present in the filter. False
+// positives are possible at the configured rate; false negatives are not.
+func (f *BloomFilter) Contains(hash string) bool {
+	h1, h2 := splitHash(hash)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitHash derives two independent 64-bit digests from hash using FNV-1a,
+// combined via double hashing (Kirsch-Mitzenmacher) to simulate k
+// independent hash functions without computing k real ones.
+func splitHash(hash string) (uint64, uint64) {
+	upper := strings.ToUpper(hash)
+
+	h1 := fnv.New64a()
+	h1.Write([]byte(upper))
+
+	h2 := fnv.New64a()
+	h2.Write([]byte(upper))
+	h2.Write([]byte{0x00})
+
+	return h1.Sum64(), h2.Sum64()
+}
+
+// Save serializes the filter to path.
+func (f *BloomFilter) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bloom filter file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, v := range []uint64{f.m, f.k} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("failed to write bloom filter: %w", err)
+		}
+	}
+	if err := binary.Write(w, binary.LittleEndian, f.bits); err != nil {
+		return fmt.Errorf("failed to write bloom filter: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write bloom filter: %w", err)
+	}
+	return nil
+}
+
+// LoadBloomFilter reads a filter previously written by Save.
+func LoadBloomFilter(path string) (*BloomFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bloom filter file: %w", err)
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+
+	var m, k uint64
+	if err := binary.Read(r, binary.LittleEndian, &m); err != nil {
+		return nil, fmt.Errorf("failed to read bloom filter: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &k); err != nil {
+		return nil, fmt.Errorf("failed to read bloom filter: %w", err)
+	}
+
+	bits := make([]uint64, (m+63)/64)
+	if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+		return nil, fmt.Errorf("failed to read bloom filter: %w", err)
+	}
+
+	return &BloomFilter{bits: bits, m: m, k: k}, nil
+}
+
+// BuildBloomFilterFromFile builds a BloomFilter from a Pwned Passwords
+// download in the official "hash:count" text format (one hash per line),
+// sized for falsePositiveRate.
+func BuildBloomFilterFromFile(path string, falsePositiveRate float64) (*BloomFilter, error) {
+	count, err := countLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := NewBloomFilter(count, falsePositiveRate)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pwned passwords file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		hash, _, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || hash == "" {
+			continue
+		}
+		filter.Add(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pwned passwords file: %w", err)
+	}
+
+	return filter, nil
+}
+
+func countLines(path string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open pwned passwords file: %w", err)
+	}
+	defer file.Close()
+
+	var count uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read pwned passwords file: %w", err)
+	}
+
+	return count, nil
+}
+
+// BloomOfflineSource answers offline lookups using a pre-built BloomFilter.
+type BloomOfflineSource struct {
+	filter *BloomFilter
+}
+
+// NewBloomOfflineSource wraps filter as an OfflineSource.
+func NewBloomOfflineSource(filter *BloomFilter) *BloomOfflineSource {
+	return &BloomOfflineSource{filter: filter}
+}
+
+// Contains reports whether hash may be present in the underlying filter.
+func (s *BloomOfflineSource) Contains(hash string) bool {
+	return s.filter.Contains(strings.ToUpper(hash))
+}
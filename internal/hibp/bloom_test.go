@@ -0,0 +1,75 @@
+package hibp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBloomFilterAddContains(t *testing.T) {
+	filter := NewBloomFilter(1000, 1e-6)
+
+	present := []string{
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+		"8846F7EAEE8FB117AD06BDD830B7586C",
+	}
+	for _, hash := range present {
+		filter.Add(hash)
+	}
+
+	for _, hash := range present {
+		if !filter.Contains(hash) {
+			t.Errorf("Contains(%q) = false, want true after Add", hash)
+		}
+	}
+
+	if filter.Contains("0000000000000000000000000000000000000000") {
+		t.Errorf("Contains reported a hash that was never added")
+	}
+}
+
+func TestBloomFilterContainsIsCaseInsensitive(t *testing.T) {
+	filter := NewBloomFilter(10, 1e-6)
+	filter.Add("abcdef0123456789")
+
+	if !filter.Contains("ABCDEF0123456789") {
+		t.Errorf("Contains should match regardless of hash case")
+	}
+}
+
+func TestBloomFilterSaveLoadRoundTrip(t *testing.T) {
+	filter := NewBloomFilter(1000, 1e-6)
+	hashes := []string{
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+		"8846F7EAEE8FB117AD06BDD830B7586C",
+		"31D6CFE0D16AE931B73C59D7E0C089C0",
+	}
+	for _, hash := range hashes {
+		filter.Add(hash)
+	}
+
+	path := filepath.Join(t.TempDir(), "filter.bloom")
+	if err := filter.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadBloomFilter(path)
+	if err != nil {
+		t.Fatalf("LoadBloomFilter: %v", err)
+	}
+
+	for _, hash := range hashes {
+		if !loaded.Contains(hash) {
+			t.Errorf("loaded filter lost hash %q", hash)
+		}
+	}
+}
+
+func TestBloomOfflineSourceNormalizesCase(t *testing.T) {
+	filter := NewBloomFilter(10, 1e-6)
+	filter.Add("ABCDEF0123456789")
+
+	source := NewBloomOfflineSource(filter)
+	if !source.Contains("abcdef0123456789") {
+		t.Errorf("BloomOfflineSource.Contains should normalize case like the filter does")
+	}
+}
@@ -0,0 +1,282 @@
+package hibp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// breachAPIBaseURL is the base URL for the authenticated HIBP v3 endpoints.
+// This is a distinct host and authentication scheme from the anonymous
+// k-anonymity password range API used by Client.
+const breachAPIBaseURL = "https://haveibeenpwned.com/api/v3"
+
+// Breach describes a single breach event as returned by the HIBP breaches
+// API (GET /breaches and GET /breach/{name}).
+type Breach struct {
+	Name               string   `json:"Name"`
+	Title              string   `json:"Title"`
+	Domain             string   `json:"Domain"`
+	BreachDate         string   `json:"BreachDate"`
+	AddedDate          string   `json:"AddedDate"`
+	ModifiedDate       string   `json:"ModifiedDate"`
+	PwnCount           int      `json:"PwnCount"`
+	Description        string   `json:"Description"`
+	LogoPath           string   `json:"LogoPath"`
+	DataClasses        []string `json:"DataClasses"`
+	IsVerified         bool     `json:"IsVerified"`
+	IsFabricated       bool     `json:"IsFabricated"`
+	IsSensitive        bool     `json:"IsSensitive"`
+	IsRetired          bool     `json:"IsRetired"`
+	IsSpamList         bool     `json:"IsSpamList"`
+	IsMalware          bool     `json:"IsMalware"`
+	IsSubscriptionFree bool     `json:"IsSubscriptionFree"`
+}
+
+// Paste describes a single paste an account was found in, as returned by
+// GET /pasteaccount/{account}.
+type Paste struct {
+	Source     string `json:"Source"`
+	ID         string `json:"Id"`
+	Title      string `json:"Title"`
+	Date       string `json:"Date"`
+	EmailCount int    `json:"EmailCount"`
+}
+
+// BreachClient talks to the authenticated HIBP v3 breach endpoints
+// (/breaches, /breach/{name}, /dataclasses, /breachedaccount/{account} and
+// /pasteaccount/{account}). This is a substantially different endpoint
+// family from the anonymous k-anonymity range API handled by Client, so it
+// gets its own HTTP client, base URL and rate limiter.
+type BreachClient struct {
+	httpClient  *http.Client
+	baseURL     string
+	apiKey      string
+	userAgent   string
+	limiter     *rateLimiter
+	maxRetries  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewBreachClient creates a new BreachClient authenticated with apiKey.
+func NewBreachClient(apiKey string) *BreachClient {
+	return &BreachClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseURL:     breachAPIBaseURL,
+		apiKey:      apiKey,
+		userAgent:   "hibp-checker",
+		limiter:     newRateLimiter(6 * time.Second),
+		maxRetries:  3,
+		baseBackoff: 500 * time.Millisecond,
+		maxBackoff:  10 * time.Second,
+	}
+}
+
+// get performs a rate-limited, authenticated GET against the breach API and
+// returns the raw response body. A 404 is reported as (nil, nil) since it
+// means "no results" for every endpoint in this family, not an error.
+//
+// Requests are retried with exponential backoff on 429 and 5xx responses
+// (honoring Retry-After on 429), the same treatment Client.QueryRangeRaw
+// gives the password range API -- a transient error partway through an
+// hours-long check-account run must not abort everything queried so far.
+func (c *BreachClient) get(path string, query url.Values) ([]byte, error) {
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	backoff := c.baseBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		c.limiter.Wait()
+
+		body, status, retryAfter, err := c.doRequest(reqURL)
+		if err != nil {
+			lastErr = err
+		} else {
+			switch {
+			case status == http.StatusNotFound:
+				return nil, nil
+			case status == http.StatusOK:
+				return body, nil
+			case status == http.StatusTooManyRequests:
+				lastErr = fmt.Errorf("HIBP API rate limited (429)")
+				if retryAfter > 0 {
+					backoff = retryAfter
+				}
+			case status >= 500:
+				lastErr = fmt.Errorf("HIBP API returned status %d", status)
+			default:
+				return nil, fmt.Errorf("HIBP API returned status %d", status)
+			}
+		}
+
+		if attempt == c.maxRetries {
+			break
+		}
+		delay := jitter(backoff)
+		if delay < retryAfter {
+			delay = retryAfter
+		}
+		time.Sleep(delay)
+		backoff = nextBackoff(backoff, c.maxBackoff)
+	}
+
+	return nil, fmt.Errorf("failed to query HIBP API after %d attempts: %w", c.maxRetries+1, lastErr)
+}
+
+// doRequest performs a single attempt, returning the response body (on
+// 200), status code, and any Retry-After delay.
+func (c *BreachClient) doRequest(reqURL string) (body []byte, status int, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("hibp-api-key", c.apiKey)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to query HIBP API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to read response body: %w", err)
+		}
+	}
+
+	return body, resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+// ListBreaches returns every breach HIBP knows about. If domain is
+// non-empty, results are restricted to that domain. Unverified breaches are
+// included only if includeUnverified is true.
+func (c *BreachClient) ListBreaches(domain string, includeUnverified bool) ([]Breach, error) {
+	query := url.Values{}
+	if domain != "" {
+		query.Set("domain", domain)
+	}
+	if includeUnverified {
+		query.Set("includeUnverified", "true")
+	}
+
+	body, err := c.get("/breaches", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var breaches []Breach
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &breaches); err != nil {
+			return nil, fmt.Errorf("failed to parse breaches response: %w", err)
+		}
+	}
+	return breaches, nil
+}
+
+// GetBreach returns a single breach by its HIBP name, or nil if no breach
+// with that name exists.
+func (c *BreachClient) GetBreach(name string) (*Breach, error) {
+	body, err := c.get("/breach/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	var breach Breach
+	if err := json.Unmarshal(body, &breach); err != nil {
+		return nil, fmt.Errorf("failed to parse breach response: %w", err)
+	}
+	return &breach, nil
+}
+
+// DataClasses returns every data class HIBP tracks (e.g. "Email addresses",
+// "Passwords").
+func (c *BreachClient) DataClasses() ([]string, error) {
+	body, err := c.get("/dataclasses", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []string
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &classes); err != nil {
+			return nil, fmt.Errorf("failed to parse data classes response: %w", err)
+		}
+	}
+	return classes, nil
+}
+
+// BreachedAccount returns the breaches an account appears in. If domain is
+// non-empty, results are restricted to that domain. If truncateResponse is
+// true, only the breach Name fields are populated. An account with no
+// breaches returns an empty, non-nil slice.
+func (c *BreachClient) BreachedAccount(account, domain string, truncateResponse, includeUnverified bool) ([]Breach, error) {
+	query := url.Values{}
+	if domain != "" {
+		query.Set("domain", domain)
+	}
+	if truncateResponse {
+		query.Set("truncateResponse", "true")
+	}
+	if includeUnverified {
+		query.Set("includeUnverified", "true")
+	}
+
+	body, err := c.get("/breachedaccount/"+url.PathEscape(account), query)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return []Breach{}, nil
+	}
+
+	if truncateResponse {
+		var names []struct {
+			Name string `json:"Name"`
+		}
+		if err := json.Unmarshal(body, &names); err != nil {
+			return nil, fmt.Errorf("failed to parse breached account response: %w", err)
+		}
+		breaches := make([]Breach, len(names))
+		for i, n := range names {
+			breaches[i] = Breach{Name: n.Name}
+		}
+		return breaches, nil
+	}
+
+	var breaches []Breach
+	if err := json.Unmarshal(body, &breaches); err != nil {
+		return nil, fmt.Errorf("failed to parse breached account response: %w", err)
+	}
+	return breaches, nil
+}
+
+// PasteAccount returns the pastes an account appears in. An account with no
+// pastes returns an empty, non-nil slice.
+func (c *BreachClient) PasteAccount(account string) ([]Paste, error) {
+	body, err := c.get("/pasteaccount/"+url.PathEscape(account), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return []Paste{}, nil
+	}
+
+	var pastes []Paste
+	if err := json.Unmarshal(body, &pastes); err != nil {
+		return nil, fmt.Errorf("failed to parse paste account response: %w", err)
+	}
+	return pastes, nil
+}
@@ -13,29 +13,97 @@ import (
 
 // Checker handles the password checking logic
 type Checker struct {
-	client       *Client
-	exposedUsers map[string]bool // only stores accounts that are exposed
-	userHashes   map[string][]string // hash -> list of accounts with that hash
-	cacheMu      sync.RWMutex
+	client            *Client
+	offline           OfflineSource        // optional local pre-check, consulted before any network query
+	offlineOnly       bool                 // if true, never query the network even on a possible offline hit
+	exposedUsers      map[string]bool      // only stores accounts that are exposed
+	userHashes        map[hashKey][]string // (mode, hash) -> list of accounts with that hash
+	completedPrefixes map[prefixJob]bool   // prefix jobs already queried in a prior run
+	cacheMu           sync.RWMutex
+}
+
+// CheckerOption customizes a Checker built by NewChecker.
+type CheckerOption func(*Checker)
+
+// WithClient overrides the default Client, e.g. to configure retries,
+// rate limiting, padding, or caching via ClientOptions.
+func WithClient(client *Client) CheckerOption {
+	return func(c *Checker) { c.client = client }
+}
+
+// WithOfflineSource consults source before any network query: a definitive
+// miss (no false negatives) skips the network entirely, while a hit is
+// still confirmed over the network unless WithOfflineOnly is also set.
+func WithOfflineSource(source OfflineSource) CheckerOption {
+	return func(c *Checker) { c.offline = source }
+}
+
+// WithOfflineOnly, combined with WithOfflineSource, makes the Checker rely
+// solely on the offline source -- accepting its false-positive rate -- so
+// a fully air-gapped audit never touches the network.
+func WithOfflineOnly(enabled bool) CheckerOption {
+	return func(c *Checker) { c.offlineOnly = enabled }
 }
 
 // NewChecker creates a new Checker instance
-func NewChecker() *Checker {
-	return &Checker{
+func NewChecker(opts ...CheckerOption) *Checker {
+	c := &Checker{
 		client:       NewClient(),
 		exposedUsers: make(map[string]bool),
-		userHashes:   make(map[string][]string),
+		userHashes:   make(map[hashKey][]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// LoadState seeds the Checker from a state journal previously written with
+// --state, so a resumed run skips already-queried prefixes and does not
+// re-emit accounts already reported. Every account already emitted is
+// replayed through resultWriter so a freshly-opened (and therefore
+// truncated) --output file still ends up with the full cumulative set of
+// results, not just the ones newly matched in this run. It is not an error
+// for path to not exist yet -- that just means there is no prior state to
+// resume from.
+func (c *Checker) LoadState(path string, resultWriter *ResultWriter) error {
+	completed, emitted, err := readJournal(path)
+	if err != nil {
+		return err
+	}
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	c.completedPrefixes = completed
+	for account := range emitted {
+		c.exposedUsers[account] = true
+		if err := resultWriter.Write(account); err != nil {
+			return fmt.Errorf("failed to replay account %s from state: %w", account, err)
+		}
 	}
+
+	return nil
 }
 
 // User represents an account with its hash
 type User struct {
-	Account string
-	Hash    string
+	Account  string
+	Hash     string
+	HashType Mode
 }
 
-// prefixJob represents a hash prefix to query
+// hashKey indexes userHashes by both the hash and the mode it was queried
+// under, so a single run can consolidate mixed SHA-1/NTLM corpora without
+// a SHA-1 hash colliding with an NTLM hash of equal value.
+type hashKey struct {
+	mode Mode
+	hash string
+}
+
+// prefixJob represents a hash prefix to query, in a given mode
 type prefixJob struct {
+	mode   Mode
 	prefix string
 }
 
@@ -76,21 +144,36 @@ func (rw *ResultWriter) Count() int {
 	return rw.count
 }
 
-// CheckFile reads a file and checks all hashes against HIBP
-func (c *Checker) CheckFile(filename, delimiter string, skipHeader bool, workers int, limit int, resultWriter *ResultWriter) (int, error) {
-	users, err := c.loadUsers(filename, delimiter, skipHeader, limit)
+// CheckFile reads a file and checks all hashes against HIBP. format selects
+// how each line is parsed (see InputFormat); hashColumn is only consulted
+// by FormatPot. mode is the hash mode to assume for formats that don't
+// imply one (account-hash, hash-list, pot, plaintext); pass ModeAuto to
+// infer SHA-1 vs NTLM per line from hash length. If journal is non-nil,
+// every completed prefix and emitted account is checkpointed to it, and a
+// write failure aborts the run instead of being logged and ignored.
+func (c *Checker) CheckFile(filename, delimiter string, skipHeader bool, workers int, limit int, format InputFormat, hashColumn int, mode Mode, resultWriter *ResultWriter, journal *StateJournal) (int, error) {
+	users, err := c.loadUsers(filename, delimiter, skipHeader, limit, format, hashColumn, mode)
 	if err != nil {
 		return 0, err
 	}
 
+	if c.offline != nil {
+		users, err = c.applyOfflineSource(users, resultWriter, journal)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Build a map of hash -> accounts and collect unique prefixes
-	prefixesToQuery := c.buildHashIndex(users)
+	prefixesToQuery := c.filterCompletedPrefixes(c.buildHashIndex(users))
 
 	fmt.Printf("Found %d users, %d unique hash prefixes to query\n", len(users), len(prefixesToQuery))
 
 	// Query all prefixes concurrently - this now checks against userHashes directly
 	if len(prefixesToQuery) > 0 {
-		c.queryPrefixesConcurrently(prefixesToQuery, workers, resultWriter)
+		if err := c.queryPrefixesConcurrently(prefixesToQuery, workers, resultWriter, journal); err != nil {
+			return 0, err
+		}
 	}
 
 	// Count exposed users
@@ -101,10 +184,100 @@ func (c *Checker) CheckFile(filename, delimiter string, skipHeader bool, workers
 	return exposedCount, nil
 }
 
-// buildHashIndex builds a map of hash -> accounts and returns unique prefixes
-func (c *Checker) buildHashIndex(users []User) []string {
-	seen := make(map[string]bool)
-	var prefixes []string
+// applyOfflineSource consults the offline source for every user, dropping
+// definitive misses (no false negatives means nothing to confirm) and
+// resolving definitive hits directly when offlineOnly is set. Remaining
+// users -- possible hits that still need network confirmation -- are
+// returned for the normal prefix-query path.
+func (c *Checker) applyOfflineSource(users []User, resultWriter *ResultWriter, journal *StateJournal) ([]User, error) {
+	remaining := users[:0]
+
+	for _, user := range users {
+		// Skip computer accounts, as buildHashIndex does
+		if strings.HasSuffix(user.Account, "$") {
+			continue
+		}
+
+		hash := strings.ToUpper(strings.TrimSpace(user.Hash))
+		if hash == "" || !c.offline.Contains(hash) {
+			continue
+		}
+
+		if !c.offlineOnly {
+			remaining = append(remaining, user)
+			continue
+		}
+
+		if err := c.markExposed(user.Account, resultWriter, journal); err != nil {
+			return nil, err
+		}
+	}
+
+	return remaining, nil
+}
+
+// markExposed records account as exposed, if not already, streaming it to
+// resultWriter and checkpointing it to journal (when set). It is safe for
+// concurrent use.
+func (c *Checker) markExposed(account string, resultWriter *ResultWriter, journal *StateJournal) error {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if c.exposedUsers[account] {
+		return nil
+	}
+	c.exposedUsers[account] = true
+	fmt.Printf("[EXPOSED] %s\n", account)
+
+	if err := resultWriter.Write(account); err != nil {
+		return fmt.Errorf("failed to write result for %s: %w", account, err)
+	}
+	if journal != nil {
+		if err := journal.RecordAccount(account); err != nil {
+			return fmt.Errorf("failed to checkpoint account %s: %w", account, err)
+		}
+	}
+
+	return nil
+}
+
+// filterCompletedPrefixes drops prefix jobs already recorded as complete in
+// a loaded state journal.
+func (c *Checker) filterCompletedPrefixes(jobs []prefixJob) []prefixJob {
+	if len(c.completedPrefixes) == 0 {
+		return jobs
+	}
+
+	remaining := jobs[:0]
+	for _, job := range jobs {
+		if !c.completedPrefixes[job] {
+			remaining = append(remaining, job)
+		}
+	}
+	return remaining
+}
+
+// ExposedAccounts returns the accounts found to be exposed so far.
+func (c *Checker) ExposedAccounts() []string {
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+
+	accounts := make([]string, 0, len(c.exposedUsers))
+	for account := range c.exposedUsers {
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// buildHashIndex builds a map of (mode, hash) -> accounts and returns the
+// unique (mode, prefix) jobs to query
+func (c *Checker) buildHashIndex(users []User) []prefixJob {
+	type seenKey struct {
+		mode   Mode
+		prefix string
+	}
+	seen := make(map[seenKey]bool)
+	var jobs []prefixJob
 
 	for _, user := range users {
 		// Skip computer accounts and empty hashes
@@ -117,40 +290,66 @@ func (c *Checker) buildHashIndex(users []User) []string {
 			continue
 		}
 
-		// Store hash -> account mapping
-		c.userHashes[hash] = append(c.userHashes[hash], user.Account)
+		mode := user.HashType
+		if mode == "" {
+			mode = ModeNTLM
+		}
+
+		// Store (mode, hash) -> account mapping
+		key := hashKey{mode: mode, hash: hash}
+		c.userHashes[key] = append(c.userHashes[key], user.Account)
 
 		prefix := hash[:5]
-		if !seen[prefix] {
-			seen[prefix] = true
-			prefixes = append(prefixes, prefix)
+		sk := seenKey{mode: mode, prefix: prefix}
+		if !seen[sk] {
+			seen[sk] = true
+			jobs = append(jobs, prefixJob{mode: mode, prefix: prefix})
 		}
 	}
 
-	return prefixes
+	return jobs
 }
 
-// queryPrefixesConcurrently queries HIBP API for all prefixes using worker pool
-// It checks matches against userHashes inline to avoid storing all HIBP results
-func (c *Checker) queryPrefixesConcurrently(prefixes []string, workers int, resultWriter *ResultWriter) {
+// queryPrefixesConcurrently queries HIBP API for all prefix jobs using a
+// worker pool. It checks matches against userHashes inline to avoid storing
+// all HIBP results. A write failure (to resultWriter or journal) aborts the
+// run: remaining queued jobs are drained without being queried and the
+// first such error is returned.
+func (c *Checker) queryPrefixesConcurrently(prefixJobs []prefixJob, workers int, resultWriter *ResultWriter, journal *StateJournal) error {
 	if workers < 1 {
 		workers = 1
 	}
 
-	jobs := make(chan prefixJob, len(prefixes))
+	jobs := make(chan prefixJob, len(prefixJobs))
 	var wg sync.WaitGroup
 
 	var completed atomic.Int64
-	total := len(prefixes)
+	total := len(prefixJobs)
 	startTime := time.Now()
 
+	var errOnce sync.Once
+	var firstErr error
+	aborted := make(chan struct{})
+
 	// Start workers
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for job := range jobs {
-				c.checkPrefixMatches(job.prefix, resultWriter)
+				select {
+				case <-aborted:
+					continue
+				default:
+				}
+
+				if err := c.checkPrefixMatches(job, resultWriter, journal); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						close(aborted)
+					})
+					continue
+				}
 
 				current := completed.Add(1)
 				elapsed := time.Since(startTime)
@@ -160,8 +359,8 @@ func (c *Checker) queryPrefixesConcurrently(prefixes []string, workers int, resu
 	}
 
 	// Send all jobs
-	for _, prefix := range prefixes {
-		jobs <- prefixJob{prefix: prefix}
+	for _, job := range prefixJobs {
+		jobs <- job
 	}
 	close(jobs)
 
@@ -170,16 +369,25 @@ func (c *Checker) queryPrefixesConcurrently(prefixes []string, workers int, resu
 
 	// Clear progress line
 	fmt.Print("\r\033[K")
+
+	if firstErr != nil {
+		return firstErr
+	}
+
 	fmt.Printf("Queried %d prefixes in %s using %d workers\n",
 		total, time.Since(startTime).Round(time.Millisecond), workers)
+	return nil
 }
 
-// checkPrefixMatches queries a prefix and checks for matches against user hashes
-func (c *Checker) checkPrefixMatches(prefix string, resultWriter *ResultWriter) {
-	resp, err := c.client.QueryRangeRaw(prefix)
+// checkPrefixMatches queries a prefix job and checks for matches against
+// user hashes. A query failure is logged and treated as non-fatal; a
+// failure writing a result or checkpointing state is returned so the
+// caller can abort the run rather than silently losing data.
+func (c *Checker) checkPrefixMatches(job prefixJob, resultWriter *ResultWriter, journal *StateJournal) error {
+	resp, err := c.client.QueryRangeRaw(job.prefix, job.mode)
 	if err != nil {
-		fmt.Printf("\n[ERROR] Failed to query prefix %s: %v\n", prefix, err)
-		return
+		fmt.Printf("\n[ERROR] Failed to query prefix %s (%s): %v\n", job.prefix, job.mode, err)
+		return nil
 	}
 
 	// Check each returned hash suffix against our user hashes
@@ -195,30 +403,35 @@ func (c *Checker) checkPrefixMatches(prefix string, resultWriter *ResultWriter)
 		}
 
 		// Reconstruct full hash and check if any user has it
-		fullHash := prefix + strings.ToUpper(parts[0])
+		fullHash := job.prefix + strings.ToUpper(parts[0])
 
 		c.cacheMu.RLock()
-		accounts, exists := c.userHashes[fullHash]
+		accounts, exists := c.userHashes[hashKey{mode: job.mode, hash: fullHash}]
 		c.cacheMu.RUnlock()
 
-		if exists {
-			c.cacheMu.Lock()
-			for _, account := range accounts {
-				if !c.exposedUsers[account] {
-					c.exposedUsers[account] = true
-					fmt.Printf("[EXPOSED] %s\n", account)
-					if err := resultWriter.Write(account); err != nil {
-						fmt.Printf("[ERROR] Failed to write result: %v\n", err)
-					}
-				}
+		if !exists {
+			continue
+		}
+
+		for _, account := range accounts {
+			if err := c.markExposed(account, resultWriter, journal); err != nil {
+				return err
 			}
-			c.cacheMu.Unlock()
 		}
 	}
+
+	if journal != nil {
+		if err := journal.RecordPrefix(job); err != nil {
+			return fmt.Errorf("failed to checkpoint prefix %s: %w", job.prefix, err)
+		}
+	}
+
+	return nil
 }
 
-// loadUsers reads the input file and returns a slice of Users
-func (c *Checker) loadUsers(filename, delimiter string, skipHeader bool, limit int) ([]User, error) {
+// loadUsers reads the input file and returns a slice of Users, parsing each
+// line according to format (see InputFormat).
+func (c *Checker) loadUsers(filename, delimiter string, skipHeader bool, limit int, format InputFormat, hashColumn int, mode Mode) ([]User, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -242,15 +455,12 @@ func (c *Checker) loadUsers(filename, delimiter string, skipHeader bool, limit i
 		}
 		firstLine = false
 
-		parts := strings.SplitN(line, delimiter, 2)
-		if len(parts) != 2 {
+		user, ok := parseLine(line, format, delimiter, hashColumn, mode)
+		if !ok {
 			continue
 		}
 
-		users = append(users, User{
-			Account: parts[0],
-			Hash:    parts[1],
-		})
+		users = append(users, user)
 
 		// Stop if we've reached the limit
 		if limit > 0 && len(users) >= limit {
@@ -3,45 +3,275 @@ package hibp
 import (
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Mode selects which HIBP Pwned Passwords hashing scheme to query: the API
+// defaults to SHA-1, and NTLM is requested with a `mode=ntlm` query param.
+type Mode string
+
+const (
+	ModeSHA1 Mode = "sha1"
+	ModeNTLM Mode = "ntlm"
+)
+
+// ClientOptions configures Client's retry, rate limiting, padding and
+// caching behavior.
+type ClientOptions struct {
+	// MaxRetries is how many times a request is retried after a 429 or 5xx
+	// response, or a transport-level error.
+	MaxRetries int
+	// BaseBackoff is the initial delay before the first retry; it doubles
+	// (with jitter) on each subsequent attempt, capped at MaxBackoff.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the exponential backoff delay between retries.
+	MaxBackoff time.Duration
+	// RequestsPerSecond caps the request rate shared across all callers of
+	// the client. Zero means unlimited.
+	RequestsPerSecond float64
+	// AddPadding sets the Add-Padding header, which mitigates traffic
+	// analysis of prefix queries by asking the API to pad its response.
+	AddPadding bool
+	// CacheDir, if set, caches each prefix's response body and ETag on
+	// disk so re-scanning the same corpus re-validates with If-None-Match
+	// instead of re-downloading.
+	CacheDir string
+}
+
+// DefaultClientOptions returns the options NewClient uses when none are
+// given.
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		MaxRetries:  3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+	}
+}
+
+// ClientOption customizes a Client built by NewClient.
+type ClientOption func(*ClientOptions)
+
+// WithMaxRetries sets how many times a failed request is retried.
+func WithMaxRetries(n int) ClientOption {
+	return func(o *ClientOptions) { o.MaxRetries = n }
+}
+
+// WithBackoff sets the base and max exponential backoff delays.
+func WithBackoff(base, max time.Duration) ClientOption {
+	return func(o *ClientOptions) { o.BaseBackoff = base; o.MaxBackoff = max }
+}
+
+// WithRequestsPerSecond caps the request rate shared across all workers.
+func WithRequestsPerSecond(rps float64) ClientOption {
+	return func(o *ClientOptions) { o.RequestsPerSecond = rps }
+}
+
+// WithAddPadding enables the Add-Padding header.
+func WithAddPadding(enabled bool) ClientOption {
+	return func(o *ClientOptions) { o.AddPadding = enabled }
+}
+
+// WithCacheDir enables on-disk ETag caching under dir.
+func WithCacheDir(dir string) ClientOption {
+	return func(o *ClientOptions) { o.CacheDir = dir }
+}
+
 // Client handles HTTP requests to the HIBP API
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	opts       ClientOptions
+	limiter    *rateLimiter
 }
 
 // NewClient creates a new HIBP API client
-func NewClient() *Client {
+func NewClient(opts ...ClientOption) *Client {
+	options := DefaultClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 		baseURL: "https://api.pwnedpasswords.com/range",
+		opts:    options,
+		limiter: newRateLimiterRPS(options.RequestsPerSecond),
+	}
+}
+
+// QueryRangeRaw queries the HIBP API with a hash prefix in the given mode
+// and returns the raw response. This avoids building a map of all results,
+// saving memory.
+//
+// Requests are rate-limited, retried with exponential backoff on 429 and
+// 5xx responses (honoring Retry-After on 429), and -- if CacheDir is set --
+// validated against a cached ETag so an unchanged prefix costs only a
+// round trip, not a full download.
+func (c *Client) QueryRangeRaw(hashPrefix string, mode Mode) (string, error) {
+	url := fmt.Sprintf("%s/%s", c.baseURL, hashPrefix)
+	if mode == ModeNTLM {
+		url += "?mode=ntlm"
+	}
+
+	cacheKey := string(mode) + "-" + hashPrefix
+	cachedBody, cachedETag := c.readCache(cacheKey)
+
+	backoff := c.opts.BaseBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		c.limiter.Wait()
+
+		body, status, etag, retryAfter, err := c.doRequest(url, cachedETag)
+		if err != nil {
+			lastErr = err
+		} else {
+			switch status {
+			case http.StatusNotModified:
+				return cachedBody, nil
+			case http.StatusOK:
+				c.writeCache(cacheKey, body, etag)
+				return body, nil
+			case http.StatusTooManyRequests:
+				lastErr = fmt.Errorf("HIBP API rate limited (429)")
+				if retryAfter > 0 {
+					backoff = retryAfter
+				}
+			default:
+				if status >= 500 {
+					lastErr = fmt.Errorf("HIBP API returned status %d", status)
+				} else {
+					return "", fmt.Errorf("HIBP API returned status %d", status)
+				}
+			}
+		}
+
+		if attempt == c.opts.MaxRetries {
+			break
+		}
+		delay := jitter(backoff)
+		if delay < retryAfter {
+			delay = retryAfter
+		}
+		time.Sleep(delay)
+		backoff = nextBackoff(backoff, c.opts.MaxBackoff)
 	}
+
+	return "", fmt.Errorf("failed to query HIBP API after %d attempts: %w", c.opts.MaxRetries+1, lastErr)
 }
 
-// QueryRangeRaw queries the HIBP API with a hash prefix and returns raw response
-// This avoids building a map of all results, saving memory
-func (c *Client) QueryRangeRaw(hashPrefix string) (string, error) {
-	url := fmt.Sprintf("%s/%s?mode=ntlm", c.baseURL, hashPrefix)
+// doRequest performs a single attempt, returning the response body (on
+// 200), status code, response ETag, and any Retry-After delay.
+func (c *Client) doRequest(url, ifNoneMatch string) (body string, status int, etag string, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", 0, "", 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	if c.opts.AddPadding {
+		req.Header.Set("Add-Padding", "true")
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to query HIBP API: %w", err)
+		return "", 0, "", 0, fmt.Errorf("failed to query HIBP API: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HIBP API returned status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusOK {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", 0, "", 0, fmt.Errorf("failed to read response body: %w", err)
+		}
+		body = string(bodyBytes)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, resp.Header.Get("ETag"), parseRetryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+// parseRetryAfter parses a Retry-After header, which HIBP sends as a
+// number of seconds.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// nextBackoff doubles delay, capped at max.
+func nextBackoff(delay, max time.Duration) time.Duration {
+	next := delay * 2
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+// jitter randomizes delay by up to +/-25% to avoid retry storms across
+// concurrent workers all backing off in lockstep.
+func jitter(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	spread := delay / 2
+	return delay - spread/2 + time.Duration(rand.Int63n(int64(spread)+1))
+}
+
+// cachePaths returns the body and ETag file paths for a cache key, or
+// ("", "") if caching is disabled.
+func (c *Client) cachePaths(key string) (bodyPath, etagPath string) {
+	if c.opts.CacheDir == "" {
+		return "", ""
+	}
+	base := filepath.Join(c.opts.CacheDir, key)
+	return base + ".body", base + ".etag"
+}
+
+// readCache returns a previously cached response body and ETag for key, or
+// ("", "") on a cache miss.
+func (c *Client) readCache(key string) (body, etag string) {
+	bodyPath, etagPath := c.cachePaths(key)
+	if bodyPath == "" {
+		return "", ""
+	}
+
+	bodyBytes, err := os.ReadFile(bodyPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", ""
 	}
+	etagBytes, _ := os.ReadFile(etagPath)
+
+	return string(bodyBytes), strings.TrimSpace(string(etagBytes))
+}
 
-	return string(body), nil
+// writeCache persists a response body and ETag for key. Cache writes are
+// best-effort: a failure just means the next run re-downloads this prefix,
+// not that any result is lost.
+func (c *Client) writeCache(key, body, etag string) {
+	bodyPath, etagPath := c.cachePaths(key)
+	if bodyPath == "" || etag == "" {
+		return
+	}
+
+	if err := os.MkdirAll(c.opts.CacheDir, 0o755); err != nil {
+		return
+	}
+	if err := os.WriteFile(bodyPath, []byte(body), 0o644); err != nil {
+		return
+	}
+	_ = os.WriteFile(etagPath, []byte(etag), 0o644)
 }
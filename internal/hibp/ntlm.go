@@ -0,0 +1,119 @@
+package hibp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+	"strings"
+	"unicode/utf16"
+)
+
+// ntlmHash computes the NT hash (MD4 of the UTF-16LE password) used by
+// Windows and returned in upper-case hex, matching the format HIBP's NTLM
+// range API returns. The standard library has no MD4 implementation, so it
+// is implemented here rather than pulling in an external dependency for a
+// single well-known, fixed-size hash.
+func ntlmHash(password string) string {
+	utf16le := make([]byte, 0, len(password)*2)
+	for _, r := range utf16.Encode([]rune(password)) {
+		utf16le = append(utf16le, byte(r), byte(r>>8))
+	}
+
+	sum := md4Sum(utf16le)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// md4Sum computes the MD4 digest of data, as defined in RFC 1320.
+func md4Sum(data []byte) [16]byte {
+	const (
+		a0 = 0x67452301
+		b0 = 0xefcdab89
+		c0 = 0x98badcfe
+		d0 = 0x10325476
+	)
+
+	msgLen := uint64(len(data))
+	padded := append([]byte(nil), data...)
+	padded = append(padded, 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0x00)
+	}
+	var lenBytes [8]byte
+	binary.LittleEndian.PutUint64(lenBytes[:], msgLen*8)
+	padded = append(padded, lenBytes[:]...)
+
+	a, b, c, d := uint32(a0), uint32(b0), uint32(c0), uint32(d0)
+
+	for block := 0; block < len(padded); block += 64 {
+		var x [16]uint32
+		for i := 0; i < 16; i++ {
+			x[i] = binary.LittleEndian.Uint32(padded[block+i*4:])
+		}
+
+		aa, bb, cc, dd := a, b, c, d
+
+		// Round 1
+		f := func(x, y, z uint32) uint32 { return (x & y) | (^x & z) }
+		round1 := [16]int{3, 7, 11, 19, 3, 7, 11, 19, 3, 7, 11, 19, 3, 7, 11, 19}
+		for i, s := range round1 {
+			switch i % 4 {
+			case 0:
+				a = bits.RotateLeft32(a+f(b, c, d)+x[i], s)
+			case 1:
+				d = bits.RotateLeft32(d+f(a, b, c)+x[i], s)
+			case 2:
+				c = bits.RotateLeft32(c+f(d, a, b)+x[i], s)
+			case 3:
+				b = bits.RotateLeft32(b+f(c, d, a)+x[i], s)
+			}
+		}
+
+		// Round 2
+		g := func(x, y, z uint32) uint32 { return (x & y) | (x & z) | (y & z) }
+		round2Order := [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+		round2Shift := [4]int{3, 5, 9, 13}
+		for i, idx := range round2Order {
+			s := round2Shift[i%4]
+			switch i % 4 {
+			case 0:
+				a = bits.RotateLeft32(a+g(b, c, d)+x[idx]+0x5a827999, s)
+			case 1:
+				d = bits.RotateLeft32(d+g(a, b, c)+x[idx]+0x5a827999, s)
+			case 2:
+				c = bits.RotateLeft32(c+g(d, a, b)+x[idx]+0x5a827999, s)
+			case 3:
+				b = bits.RotateLeft32(b+g(c, d, a)+x[idx]+0x5a827999, s)
+			}
+		}
+
+		// Round 3
+		h := func(x, y, z uint32) uint32 { return x ^ y ^ z }
+		round3Order := [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+		round3Shift := [4]int{3, 9, 11, 15}
+		for i, idx := range round3Order {
+			s := round3Shift[i%4]
+			switch i % 4 {
+			case 0:
+				a = bits.RotateLeft32(a+h(b, c, d)+x[idx]+0x6ed9eba1, s)
+			case 1:
+				d = bits.RotateLeft32(d+h(a, b, c)+x[idx]+0x6ed9eba1, s)
+			case 2:
+				c = bits.RotateLeft32(c+h(d, a, b)+x[idx]+0x6ed9eba1, s)
+			case 3:
+				b = bits.RotateLeft32(b+h(c, d, a)+x[idx]+0x6ed9eba1, s)
+			}
+		}
+
+		a += aa
+		b += bb
+		c += cc
+		d += dd
+	}
+
+	var digest [16]byte
+	binary.LittleEndian.PutUint32(digest[0:4], a)
+	binary.LittleEndian.PutUint32(digest[4:8], b)
+	binary.LittleEndian.PutUint32(digest[8:12], c)
+	binary.LittleEndian.PutUint32(digest[12:16], d)
+	return digest
+}
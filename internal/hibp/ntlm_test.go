@@ -0,0 +1,48 @@
+package hibp
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestMD4Sum checks md4Sum against the RFC 1320 Appendix A.5 test vectors.
+func TestMD4Sum(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "043f8582f241db351ce627e153e7f0e4"},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "e33b4ddc9c38f2199c3e7b164fcc0536"},
+	}
+
+	for _, tc := range cases {
+		got := md4Sum([]byte(tc.input))
+		if hex.EncodeToString(got[:]) != tc.want {
+			t.Errorf("md4Sum(%q) = %x, want %s", tc.input, got, tc.want)
+		}
+	}
+}
+
+// TestNTLMHash checks ntlmHash against known NT hash vectors for short
+// passwords (UTF-16LE input to MD4, upper-case hex output).
+func TestNTLMHash(t *testing.T) {
+	cases := []struct {
+		password string
+		want     string
+	}{
+		{"", "31D6CFE0D16AE931B73C59D7E0C089C0"},
+		{"password", "8846F7EAEE8FB117AD06BDD830B7586C"},
+		{"Password1", "64F12CDDAA88057E06A81B54E73B949B"},
+	}
+
+	for _, tc := range cases {
+		if got := ntlmHash(tc.password); got != tc.want {
+			t.Errorf("ntlmHash(%q) = %s, want %s", tc.password, got, tc.want)
+		}
+	}
+}
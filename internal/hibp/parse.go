@@ -0,0 +1,173 @@
+package hibp
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// InputFormat selects how a line of input is parsed into a User.
+type InputFormat string
+
+const (
+	// FormatAccountHash parses "account<delimiter>hash" lines (the
+	// original, and default, format).
+	FormatAccountHash InputFormat = "account-hash"
+	// FormatHashList parses a bare hash per line.
+	FormatHashList InputFormat = "hash-list"
+	// FormatSecretsdump parses secretsdump.py-style
+	// "user:rid:lmhash:nthash:::" lines, extracting the NT hash.
+	FormatSecretsdump InputFormat = "secretsdump"
+	// FormatPot parses John the Ripper / hashcat pot files, which are
+	// colon-separated with the hash in a configurable column.
+	FormatPot InputFormat = "pot"
+	// FormatPlaintext parses a plaintext password per line and hashes it
+	// locally before querying, so only the hash prefix ever leaves the
+	// machine.
+	FormatPlaintext InputFormat = "plaintext"
+)
+
+// emptyNTHash is the NT hash of an empty password, used by secretsdump to
+// mark accounts with no password set.
+const emptyNTHash = "31d6cfe0d16ae931b73c59d7e0c089c0"
+
+// ModeAuto infers each hash's Mode from its length (32 hex chars for NTLM,
+// 40 for SHA-1) instead of assuming a fixed mode for every line. It lets a
+// single run consolidate mixed SHA-1/NTLM corpora.
+const ModeAuto Mode = "auto"
+
+// parseLine dispatches a single line of input to the parser for format,
+// returning the User it describes and whether the line yielded one.
+func parseLine(line string, format InputFormat, delimiter string, hashColumn int, mode Mode) (User, bool) {
+	switch format {
+	case FormatHashList:
+		return parseHashList(line, mode)
+	case FormatSecretsdump:
+		return parseSecretsdump(line)
+	case FormatPot:
+		return parsePot(line, hashColumn, mode)
+	case FormatPlaintext:
+		return parsePlaintext(line, mode)
+	default:
+		return parseAccountHash(line, delimiter, mode)
+	}
+}
+
+// resolveMode returns mode unchanged unless it is ModeAuto, in which case it
+// infers SHA-1 vs NTLM from the hash's length.
+func resolveMode(mode Mode, hash string) (Mode, bool) {
+	if mode != ModeAuto {
+		return mode, true
+	}
+	switch len(strings.TrimSpace(hash)) {
+	case 32:
+		return ModeNTLM, true
+	case 40:
+		return ModeSHA1, true
+	default:
+		return "", false
+	}
+}
+
+// parseAccountHash parses "account<delimiter>hash" lines.
+func parseAccountHash(line, delimiter string, mode Mode) (User, bool) {
+	parts := strings.SplitN(line, delimiter, 2)
+	if len(parts) != 2 {
+		return User{}, false
+	}
+
+	hash := strings.TrimSpace(parts[1])
+	resolved, ok := resolveMode(mode, hash)
+	if !ok {
+		return User{}, false
+	}
+
+	return User{Account: parts[0], Hash: hash, HashType: resolved}, true
+}
+
+// parseHashList parses a bare hash per line, using the hash itself as the
+// account label since none is given.
+func parseHashList(line string, mode Mode) (User, bool) {
+	hash := strings.TrimSpace(line)
+	if hash == "" {
+		return User{}, false
+	}
+
+	resolved, ok := resolveMode(mode, hash)
+	if !ok {
+		return User{}, false
+	}
+
+	return User{Account: hash, Hash: hash, HashType: resolved}, true
+}
+
+// parseSecretsdump parses a secretsdump.py-style line in the form
+// "user:rid:lmhash:nthash:::", extracting the NT hash. Disabled accounts
+// and accounts with no password set (the well-known empty NT hash) are
+// skipped.
+func parseSecretsdump(line string) (User, bool) {
+	fields := strings.Split(line, ":")
+	if len(fields) < 4 {
+		return User{}, false
+	}
+
+	account := fields[0]
+	if strings.Contains(strings.ToLower(account), "(disabled)") {
+		return User{}, false
+	}
+
+	ntHash := strings.TrimSpace(fields[3])
+	if ntHash == "" || strings.EqualFold(ntHash, emptyNTHash) {
+		return User{}, false
+	}
+
+	return User{Account: account, Hash: ntHash, HashType: ModeNTLM}, true
+}
+
+// parsePot parses a colon-separated John the Ripper / hashcat pot file
+// line, reading the hash from hashColumn.
+func parsePot(line string, hashColumn int, mode Mode) (User, bool) {
+	fields := strings.Split(line, ":")
+	if hashColumn < 0 || hashColumn >= len(fields) {
+		return User{}, false
+	}
+
+	hash := strings.TrimSpace(fields[hashColumn])
+	if hash == "" {
+		return User{}, false
+	}
+
+	resolved, ok := resolveMode(mode, hash)
+	if !ok {
+		return User{}, false
+	}
+
+	return User{Account: hash, Hash: hash, HashType: resolved}, true
+}
+
+// parsePlaintext hashes a plaintext password line locally in mode (ModeAuto
+// defaults to SHA-1, matching the HIBP API's own default) so the password
+// never leaves the machine, only its hash prefix does. The hash itself is
+// used as the account label so the original password isn't echoed back in
+// results.
+func parsePlaintext(line string, mode Mode) (User, bool) {
+	password := line
+	if password == "" {
+		return User{}, false
+	}
+
+	if mode == ModeAuto {
+		mode = ModeSHA1
+	}
+
+	var hash string
+	switch mode {
+	case ModeNTLM:
+		hash = ntlmHash(password)
+	default:
+		sum := sha1.Sum([]byte(password))
+		hash = strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+
+	return User{Account: hash, Hash: hash, HashType: mode}, true
+}
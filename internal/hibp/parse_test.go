@@ -0,0 +1,142 @@
+package hibp
+
+import "testing"
+
+func TestParseLine(t *testing.T) {
+	cases := []struct {
+		name       string
+		line       string
+		format     InputFormat
+		delimiter  string
+		hashColumn int
+		mode       Mode
+		want       User
+		wantOK     bool
+	}{
+		{
+			name:      "account-hash",
+			line:      "alice:5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+			format:    FormatAccountHash,
+			delimiter: ":",
+			mode:      ModeSHA1,
+			want:      User{Account: "alice", Hash: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", HashType: ModeSHA1},
+			wantOK:    true,
+		},
+		{
+			name:      "account-hash missing delimiter",
+			line:      "alice-no-hash",
+			format:    FormatAccountHash,
+			delimiter: ":",
+			mode:      ModeSHA1,
+			wantOK:    false,
+		},
+		{
+			name:   "hash-list",
+			line:   "8846F7EAEE8FB117AD06BDD830B7586C",
+			format: FormatHashList,
+			mode:   ModeNTLM,
+			want:   User{Account: "8846F7EAEE8FB117AD06BDD830B7586C", Hash: "8846F7EAEE8FB117AD06BDD830B7586C", HashType: ModeNTLM},
+			wantOK: true,
+		},
+		{
+			name:   "hash-list blank line",
+			line:   "   ",
+			format: FormatHashList,
+			mode:   ModeNTLM,
+			wantOK: false,
+		},
+		{
+			name:   "secretsdump extracts NT hash",
+			line:   "bob:1001:aad3b435b51404eeaad3b435b51404ee:8846f7eaee8fb117ad06bdd830b7586c:::",
+			format: FormatSecretsdump,
+			want:   User{Account: "bob", Hash: "8846f7eaee8fb117ad06bdd830b7586c", HashType: ModeNTLM},
+			wantOK: true,
+		},
+		{
+			name:   "secretsdump skips disabled accounts",
+			line:   "carol (disabled):1002:aad3b435b51404eeaad3b435b51404ee:8846f7eaee8fb117ad06bdd830b7586c:::",
+			format: FormatSecretsdump,
+			wantOK: false,
+		},
+		{
+			name:   "secretsdump skips empty-password NT hash",
+			line:   "dave:1003:aad3b435b51404eeaad3b435b51404ee:31d6cfe0d16ae931b73c59d7e0c089c0:::",
+			format: FormatSecretsdump,
+			wantOK: false,
+		},
+		{
+			name:   "secretsdump too few fields",
+			line:   "eve:1004",
+			format: FormatSecretsdump,
+			wantOK: false,
+		},
+		{
+			name:       "pot reads configured column",
+			line:       "8846f7eaee8fb117ad06bdd830b7586c:password",
+			format:     FormatPot,
+			hashColumn: 0,
+			mode:       ModeNTLM,
+			want:       User{Account: "8846f7eaee8fb117ad06bdd830b7586c", Hash: "8846f7eaee8fb117ad06bdd830b7586c", HashType: ModeNTLM},
+			wantOK:     true,
+		},
+		{
+			name:       "pot column out of range",
+			line:       "8846f7eaee8fb117ad06bdd830b7586c:password",
+			format:     FormatPot,
+			hashColumn: 5,
+			mode:       ModeNTLM,
+			wantOK:     false,
+		},
+		{
+			name:   "plaintext hashes locally with sha1",
+			line:   "password",
+			format: FormatPlaintext,
+			mode:   ModeSHA1,
+			want:   User{Account: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", Hash: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", HashType: ModeSHA1},
+			wantOK: true,
+		},
+		{
+			name:   "plaintext hashes locally with ntlm",
+			line:   "password",
+			format: FormatPlaintext,
+			mode:   ModeNTLM,
+			want:   User{Account: "8846F7EAEE8FB117AD06BDD830B7586C", Hash: "8846F7EAEE8FB117AD06BDD830B7586C", HashType: ModeNTLM},
+			wantOK: true,
+		},
+		{
+			name:   "auto mode infers ntlm from length",
+			line:   "8846F7EAEE8FB117AD06BDD830B7586C",
+			format: FormatHashList,
+			mode:   ModeAuto,
+			want:   User{Account: "8846F7EAEE8FB117AD06BDD830B7586C", Hash: "8846F7EAEE8FB117AD06BDD830B7586C", HashType: ModeNTLM},
+			wantOK: true,
+		},
+		{
+			name:   "auto mode infers sha1 from length",
+			line:   "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+			format: FormatHashList,
+			mode:   ModeAuto,
+			want:   User{Account: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", Hash: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", HashType: ModeSHA1},
+			wantOK: true,
+		},
+		{
+			name:   "auto mode rejects unrecognized length",
+			line:   "deadbeef",
+			format: FormatHashList,
+			mode:   ModeAuto,
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseLine(tc.line, tc.format, tc.delimiter, tc.hashColumn, tc.mode)
+			if ok != tc.wantOK {
+				t.Fatalf("parseLine(%q) ok = %v, want %v", tc.line, ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Errorf("parseLine(%q) = %+v, want %+v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package hibp
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a single-slot token bucket that enforces a minimum
+// interval between successive Wait calls. It is safe for concurrent use so
+// a single limiter can be shared across all workers in a pool.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter creates a rateLimiter enforcing a fixed interval between
+// requests. An interval of zero disables limiting.
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{interval: interval}
+}
+
+// newRateLimiterRPS creates a rateLimiter enforcing a maximum request rate.
+// A rate of zero or less disables limiting.
+func newRateLimiterRPS(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return newRateLimiter(0)
+	}
+	return newRateLimiter(time.Duration(float64(time.Second) / requestsPerSecond))
+}
+
+// Wait blocks until the next request is allowed under the configured rate.
+func (r *rateLimiter) Wait() {
+	if r.interval <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(r.next) {
+		time.Sleep(r.next.Sub(now))
+		now = time.Now()
+	}
+	r.next = now.Add(r.interval)
+}
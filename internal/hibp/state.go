@@ -0,0 +1,125 @@
+package hibp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Journal entry kinds. Each line is "<kind>\t<fields...>".
+const (
+	journalPrefixEntry  = "P" // a completed (mode, prefix) query
+	journalAccountEntry = "A" // an emitted exposed account
+)
+
+// syncEveryWrites bounds how often StateJournal fsyncs to disk: often
+// enough that a crash loses only a small amount of progress, rarely enough
+// that journaling doesn't dominate the run's wall-clock time.
+const syncEveryWrites = 50
+
+// StateJournal is an append-only checkpoint file recording completed hash
+// prefixes and emitted exposed accounts, so an interrupted run can resume
+// without re-querying prefixes or re-emitting results.
+type StateJournal struct {
+	file   *os.File
+	mu     sync.Mutex
+	writes int
+}
+
+// OpenStateJournal opens (creating if necessary) the journal at path for
+// appending new entries.
+func OpenStateJournal(path string) (*StateJournal, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file: %w", err)
+	}
+	return &StateJournal{file: file}, nil
+}
+
+// readJournal reads every entry from the journal at path, returning the
+// completed prefix jobs and the accounts already emitted. A missing file is
+// not an error: it simply yields empty results, so a fresh run with
+// --state behaves the same as one with no state file at all.
+func readJournal(path string) (map[prefixJob]bool, map[string]bool, error) {
+	completed := make(map[prefixJob]bool)
+	emitted := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return completed, emitted, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open state file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		kind, rest, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case journalPrefixEntry:
+			mode, prefix, ok := strings.Cut(rest, "\t")
+			if !ok {
+				continue
+			}
+			completed[prefixJob{mode: Mode(mode), prefix: prefix}] = true
+		case journalAccountEntry:
+			emitted[rest] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	return completed, emitted, nil
+}
+
+// RecordPrefix appends a completed-prefix entry, returning an error if the
+// write (or periodic fsync) fails. Callers must only record a prefix after
+// every account match it yielded has already been durably recorded, so a
+// crash never leaves a "completed" prefix with unrecorded accounts.
+func (s *StateJournal) RecordPrefix(job prefixJob) error {
+	return s.append(fmt.Sprintf("%s\t%s\t%s\n", journalPrefixEntry, job.mode, job.prefix))
+}
+
+// RecordAccount appends an emitted-account entry, returning an error if the
+// write (or periodic fsync) fails.
+func (s *StateJournal) RecordAccount(account string) error {
+	return s.append(fmt.Sprintf("%s\t%s\n", journalAccountEntry, account))
+}
+
+func (s *StateJournal) append(line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write state entry: %w", err)
+	}
+
+	s.writes++
+	if s.writes%syncEveryWrites == 0 {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync state file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close fsyncs any unsynced entries and closes the underlying file.
+func (s *StateJournal) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,103 @@
+package hibp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStateJournalRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+
+	journal, err := OpenStateJournal(path)
+	if err != nil {
+		t.Fatalf("OpenStateJournal: %v", err)
+	}
+
+	if err := journal.RecordPrefix(prefixJob{mode: ModeNTLM, prefix: "8846F"}); err != nil {
+		t.Fatalf("RecordPrefix: %v", err)
+	}
+	if err := journal.RecordPrefix(prefixJob{mode: ModeSHA1, prefix: "5BAA6"}); err != nil {
+		t.Fatalf("RecordPrefix: %v", err)
+	}
+	if err := journal.RecordAccount("alice"); err != nil {
+		t.Fatalf("RecordAccount: %v", err)
+	}
+	if err := journal.RecordAccount("bob"); err != nil {
+		t.Fatalf("RecordAccount: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	completed, emitted, err := readJournal(path)
+	if err != nil {
+		t.Fatalf("readJournal: %v", err)
+	}
+
+	wantCompleted := map[prefixJob]bool{
+		{mode: ModeNTLM, prefix: "8846F"}: true,
+		{mode: ModeSHA1, prefix: "5BAA6"}: true,
+	}
+	if len(completed) != len(wantCompleted) {
+		t.Fatalf("completed = %v, want %v", completed, wantCompleted)
+	}
+	for job := range wantCompleted {
+		if !completed[job] {
+			t.Errorf("missing completed prefix %+v", job)
+		}
+	}
+
+	wantEmitted := map[string]bool{"alice": true, "bob": true}
+	if len(emitted) != len(wantEmitted) {
+		t.Fatalf("emitted = %v, want %v", emitted, wantEmitted)
+	}
+	for account := range wantEmitted {
+		if !emitted[account] {
+			t.Errorf("missing emitted account %q", account)
+		}
+	}
+}
+
+func TestStateJournalAppendsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.journal")
+
+	journal, err := OpenStateJournal(path)
+	if err != nil {
+		t.Fatalf("OpenStateJournal: %v", err)
+	}
+	if err := journal.RecordAccount("alice"); err != nil {
+		t.Fatalf("RecordAccount: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	journal, err = OpenStateJournal(path)
+	if err != nil {
+		t.Fatalf("re-OpenStateJournal: %v", err)
+	}
+	if err := journal.RecordAccount("bob"); err != nil {
+		t.Fatalf("RecordAccount: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, emitted, err := readJournal(path)
+	if err != nil {
+		t.Fatalf("readJournal: %v", err)
+	}
+	if !emitted["alice"] || !emitted["bob"] {
+		t.Errorf("emitted = %v, want both alice and bob", emitted)
+	}
+}
+
+func TestReadJournalMissingFile(t *testing.T) {
+	completed, emitted, err := readJournal(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("readJournal on missing file: %v", err)
+	}
+	if len(completed) != 0 || len(emitted) != 0 {
+		t.Errorf("expected empty results for a missing journal, got completed=%v emitted=%v", completed, emitted)
+	}
+}